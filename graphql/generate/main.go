@@ -0,0 +1,242 @@
+// Command "generate-graphql" reads the model information from
+// "models/models.toml" and generates the relevant "graphql/generated.go"
+// file, the same way "models/generate" produces "models/generated.go".
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+)
+
+// TomlTable is a map from column name to relevant type.
+type TomlTable map[string]string
+
+// TomlTables is a map from table names to relevant tables.
+type TomlTables map[string]TomlTable
+
+// graphqlType maps a models.toml column type to its GraphQL type. A
+// column typed as one of the nullable "sql.Null*" wrappers maps to a bare
+// scalar; everything else is NOT NULL in the database, so it is wrapped
+// in graphql.NewNonNull.
+func graphqlType(typ string) string {
+	var scalar string
+	switch typ {
+	case "int", "sql.NullInt64":
+		scalar = "graphql.Int"
+	case "string", "sql.NullString":
+		scalar = "graphql.String"
+	case "float64", "sql.NullFloat64":
+		scalar = "graphql.Float"
+	case "bool", "sql.NullBool":
+		scalar = "graphql.Boolean"
+	case "time.Time", "sql.NullTime":
+		scalar = "graphql.DateTime"
+	default:
+		scalar = "graphql.String"
+	}
+	if strings.HasPrefix(typ, "sql.Null") {
+		return scalar
+	}
+	return "graphql.NewNonNull(" + scalar + ")"
+}
+
+// isNullable reports whether typ is one of the "sql.Null*" wrappers
+// models/generate uses for nullable columns.
+func isNullable(typ string) bool {
+	return strings.HasPrefix(typ, "sql.Null")
+}
+
+// nullAccessor is the field of a "sql.Null*" wrapper struct holding its
+// underlying value, e.g. sql.NullString.String.
+func nullAccessor(typ string) string {
+	switch typ {
+	case "sql.NullInt64":
+		return "Int64"
+	case "sql.NullFloat64":
+		return "Float64"
+	case "sql.NullBool":
+		return "Bool"
+	case "sql.NullTime":
+		return "Time"
+	default:
+		return "String"
+	}
+}
+
+// modelField derives the exported Go struct field name models/generate
+// gives a column, so a resolver can read it off the row models.GetX
+// returned — the same SnakeToGocase(_, export=true) convention, where a
+// lone "id" part becomes "ID".
+func modelField(s string) string {
+	parts := strings.Split(s, "_")
+	result := strings.Builder{}
+	for _, part := range parts {
+		if part == "id" {
+			part = "ID"
+		} else {
+			part = strings.Title(part)
+		}
+		result.WriteString(part)
+	}
+	return result.String()
+}
+
+// snakeToCamel translates a snake_case column name to a lowerCamelCase
+// GraphQL field name.
+func snakeToCamel(s string) string {
+	parts := strings.Split(s, "_")
+	result := strings.Builder{}
+	for i, part := range parts {
+		if i == 0 {
+			result.WriteString(part)
+			continue
+		}
+		if part == "id" {
+			part = "Id"
+		} else {
+			part = strings.Title(part)
+		}
+		result.WriteString(part)
+	}
+	return result.String()
+}
+
+// structName derives the GraphQL type name from a table name, e.g.
+// "contests" -> "contestType".
+func structName(tableName string) string {
+	tableName = tableName[:len(tableName)-1]
+	return snakeToCamel(tableName) + "Type"
+}
+
+func typeName(tableName string) string {
+	tableName = tableName[:len(tableName)-1]
+	parts := strings.Split(tableName, "_")
+	result := strings.Builder{}
+	for _, part := range parts {
+		result.WriteString(strings.Title(part))
+	}
+	return result.String()
+}
+
+// sortedFields lists t's column names, alphabetically and excluding the
+// reserved "_"-prefixed keys models/generate uses to configure per-table
+// codegen (e.g. "_soft_delete") — those don't name a column and have no
+// business in the GraphQL schema.
+func sortedFields(t TomlTable) []string {
+	var keys []string
+	for k := range t {
+		if strings.HasPrefix(k, "_") {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Table pairs a models.toml table with the data the template needs.
+type Table struct {
+	Name   string
+	Fields TomlTable
+}
+
+var t = template.New("main")
+
+func init() {
+	t.Funcs(map[string]interface{}{
+		"struct":   structName,
+		"type":     typeName,
+		"field":    snakeToCamel,
+		"gql":      graphqlType,
+		"fields":   sortedFields,
+		"nullable": isNullable,
+		"accessor": nullAccessor,
+		"model":    modelField,
+	})
+	template.Must(t.Parse(fileTemplate))
+}
+
+const fileTemplate = `
+// Generated by "git.nkagami.me/natsukagami/kjudge/graphql/generate". DO NOT EDIT.
+//
+// One object type per "models.toml" table, field-for-field, so these can
+// never drift from the database schema they expose. A nullable ("sql.Null*")
+// column gets an explicit Resolve that unwraps it to its value or nil;
+// graphql-go's default field resolver would otherwise hand the scalar
+// serializer the sql.Null* struct itself. Types that don't map 1:1 onto a
+// table (e.g. ScoreboardRow, which is computed rather than stored) are
+// hand-written in graphql/types.go instead, which this command does not
+// touch.
+
+package graphql
+
+import (
+    "git.nkagami.me/natsukagami/kjudge/models"
+    "github.com/graphql-go/graphql"
+)
+{{range $t := .}}
+var {{$t.Name | struct}} = graphql.NewObject(graphql.ObjectConfig{
+    Name: "{{$t.Name | type}}",
+    Fields: graphql.Fields{
+{{- range $field := $t.Fields | fields}}
+{{- $typ := index $t.Fields $field}}
+        "{{$field | field}}": &graphql.Field{
+            Type: {{$typ | gql}},
+{{- if $typ | nullable}}
+            Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+                v := p.Source.(*models.{{$t.Name | type}}).{{$field | model}}
+                if !v.Valid {
+                    return nil, nil
+                }
+                return v.{{$typ | accessor}}, nil
+            },
+{{- end}}
+        },
+{{- end}}
+    },
+})
+{{end}}
+`
+
+func main() {
+	var tables TomlTables
+	if _, err := toml.DecodeFile("models/models.toml", &tables); err != nil {
+		log.Fatal(err)
+	}
+
+	var ts []Table
+	var names []string
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ts = append(ts, Table{Name: name, Fields: tables[name]})
+	}
+
+	filename := "graphql/generated.go"
+	f, err := os.Create(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := t.Execute(f, ts); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+	if err := exec.Command("go", "fmt", filename).Run(); err != nil {
+		log.Fatal(err)
+	}
+	if err := exec.Command("goimports", "-w", filename).Run(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Generated GraphQL types to %s\n", filename)
+}
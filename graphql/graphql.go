@@ -0,0 +1,196 @@
+// Package graphql exposes kjudge's data model (contests, problems,
+// submissions, users, scoreboards and clarifications) as a typed GraphQL
+// endpoint, mounted at "/graphql" next to the admin and user handlers.
+//
+// The object types in generated.go are produced from "models/models.toml"
+// by "git.nkagami.me/natsukagami/kjudge/graphql/generate", the same way
+// "models/generated.go" is produced by "models/generate". Hand-written
+// resolvers live in this file and in subscriptions.go.
+package graphql
+
+import (
+	"context"
+
+	"git.nkagami.me/natsukagami/kjudge/db"
+	"git.nkagami.me/natsukagami/kjudge/models"
+	"git.nkagami.me/natsukagami/kjudge/pubsub"
+	"github.com/graphql-go/graphql"
+	"github.com/pkg/errors"
+)
+
+// ctxKey namespaces values this package stores in a context.Context,
+// so as not to collide with keys other packages put there.
+type ctxKey int
+
+const (
+	// ctxKeyDB is the key under which a db.DBContext is stored for
+	// resolvers.
+	ctxKeyDB ctxKey = iota
+	// ctxKeyBroker is the key under which a *pubsub.Broker is stored for
+	// subscription resolvers.
+	ctxKeyBroker
+)
+
+// WithDB returns a context carrying db, for use as the root context of a
+// graphql.Do call.
+func WithDB(ctx context.Context, db db.DBContext) context.Context {
+	return context.WithValue(ctx, ctxKeyDB, db)
+}
+
+// WithBroker returns a context carrying broker, for use as the root
+// context of a graphql.Subscribe call. broker should be the same
+// pubsub.Broker the judging worker and the SSE routes publish to and
+// subscribe from, so every transport sees the same events.
+func WithBroker(ctx context.Context, broker *pubsub.Broker) context.Context {
+	return context.WithValue(ctx, ctxKeyBroker, broker)
+}
+
+// dbFromParams extracts the db.DBContext installed by WithDB.
+func dbFromParams(p graphql.ResolveParams) (db.DBContext, error) {
+	d, ok := p.Context.Value(ctxKeyDB).(db.DBContext)
+	if !ok {
+		return nil, errors.New("graphql: no database in context")
+	}
+	return d, nil
+}
+
+// brokerFromParams extracts the *pubsub.Broker installed by WithBroker.
+func brokerFromParams(p graphql.ResolveParams) (*pubsub.Broker, error) {
+	b, ok := p.Context.Value(ctxKeyBroker).(*pubsub.Broker)
+	if !ok {
+		return nil, errors.New("graphql: no broker in context")
+	}
+	return b, nil
+}
+
+// Schema is the root schema served at "/graphql".
+var Schema graphql.Schema
+
+func init() {
+	var err error
+	Schema, err = graphql.NewSchema(graphql.SchemaConfig{
+		Query:        queryType,
+		Subscription: subscriptionType,
+	})
+	if err != nil {
+		panic(errors.Wrap(err, "graphql: building schema"))
+	}
+}
+
+var queryType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Query",
+	Fields: graphql.Fields{
+		"contest": &graphql.Field{
+			Type: contestType,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+			},
+			Resolve: resolveContest,
+		},
+		"contests": &graphql.Field{
+			Type:    graphql.NewList(contestType),
+			Resolve: resolveContests,
+		},
+		"problem": &graphql.Field{
+			Type: problemType,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+			},
+			Resolve: resolveProblem,
+		},
+		"user": &graphql.Field{
+			Type: userType,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: resolveUser,
+		},
+		"submission": &graphql.Field{
+			Type: submissionType,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+			},
+			Resolve: resolveSubmission,
+		},
+		"scoreboard": &graphql.Field{
+			Type: graphql.NewList(scoreboardRowType),
+			Args: graphql.FieldConfigArgument{
+				"contestId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+			},
+			Resolve: resolveScoreboard,
+		},
+		"clarifications": &graphql.Field{
+			Type: graphql.NewList(clarificationType),
+			Args: graphql.FieldConfigArgument{
+				"contestId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+			},
+			Resolve: resolveClarifications,
+		},
+	},
+})
+
+func intArg(p graphql.ResolveParams, name string) int {
+	v, _ := p.Args[name].(int)
+	return v
+}
+
+func resolveContest(p graphql.ResolveParams) (interface{}, error) {
+	d, err := dbFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	return models.GetContest(d, intArg(p, "id"))
+}
+
+func resolveContests(p graphql.ResolveParams) (interface{}, error) {
+	d, err := dbFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	return models.GetContests(d)
+}
+
+func resolveProblem(p graphql.ResolveParams) (interface{}, error) {
+	d, err := dbFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	return models.GetProblem(d, intArg(p, "id"))
+}
+
+func resolveUser(p graphql.ResolveParams) (interface{}, error) {
+	d, err := dbFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	id, _ := p.Args["id"].(string)
+	return models.GetUser(d, id)
+}
+
+func resolveSubmission(p graphql.ResolveParams) (interface{}, error) {
+	d, err := dbFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	return models.GetSubmission(d, intArg(p, "id"))
+}
+
+func resolveScoreboard(p graphql.ResolveParams) (interface{}, error) {
+	d, err := dbFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	contest, err := models.GetContest(d, intArg(p, "contestId"))
+	if err != nil {
+		return nil, err
+	}
+	return models.Scoreboard(d, contest)
+}
+
+func resolveClarifications(p graphql.ResolveParams) (interface{}, error) {
+	d, err := dbFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	return models.GetContestClarifications(d, intArg(p, "contestId"))
+}
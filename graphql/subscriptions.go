@@ -0,0 +1,68 @@
+package graphql
+
+import (
+	"git.nkagami.me/natsukagami/kjudge/models"
+	"git.nkagami.me/natsukagami/kjudge/pubsub"
+	"github.com/graphql-go/graphql"
+)
+
+var subscriptionType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Subscription",
+	Fields: graphql.Fields{
+		"submissionVerdict": &graphql.Field{
+			Type: submissionType,
+			Args: graphql.FieldConfigArgument{
+				"problemId": &graphql.ArgumentConfig{Type: graphql.Int},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				// Subscriptions are delivered out-of-band by Subscribe;
+				// Resolve only ever runs against the already-matched event.
+				return p.Source, nil
+			},
+			Subscribe: subscribeSubmissionVerdict,
+		},
+	},
+})
+
+// subscribeSubmissionVerdict returns a channel of raw events for the
+// "submissionVerdict" field, filtered to the requested problem when given.
+// It listens on pubsub.AllSubmissionsTopic, the same firehose the judging
+// worker publishes every submission update to, so this sees exactly what
+// the SSE routes in server/contests see.
+func subscribeSubmissionVerdict(p graphql.ResolveParams) (interface{}, error) {
+	broker, err := brokerFromParams(p)
+	if err != nil {
+		return nil, err
+	}
+	problemID, hasFilter := p.Args["problemId"].(int)
+
+	raw, cancel := broker.Subscribe(pubsub.AllSubmissionsTopic())
+	out := make(chan interface{})
+	go func() {
+		defer close(out)
+		defer cancel()
+		for {
+			select {
+			case <-p.Context.Done():
+				return
+			case v, ok := <-raw:
+				if !ok {
+					return
+				}
+				s, ok := v.(*models.Submission)
+				if !ok {
+					continue
+				}
+				if hasFilter && s.ProblemID != problemID {
+					continue
+				}
+				select {
+				case out <- s:
+				case <-p.Context.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,16 @@
+package graphql
+
+import "github.com/graphql-go/graphql"
+
+// scoreboardRowType is hand-written, not generated: a scoreboard row is
+// computed by models.Scoreboard rather than read from a single table, so
+// graphql/generate (which only knows about models.toml tables) has
+// nothing to derive it from.
+var scoreboardRowType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "ScoreboardRow",
+	Fields: graphql.Fields{
+		"user":       &graphql.Field{Type: graphql.NewNonNull(userType)},
+		"totalScore": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"rank":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
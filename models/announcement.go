@@ -0,0 +1,12 @@
+package models
+
+import "github.com/pkg/errors"
+
+// Verify checks that the Announcement is well-formed before it is
+// written to the Database.
+func (r *Announcement) Verify() error {
+	if r.Content == "" {
+		return errors.New("models: announcement content must not be empty")
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package models
+
+import "github.com/pkg/errors"
+
+// Verify checks that the Clarification is well-formed before it is
+// written to the Database.
+func (r *Clarification) Verify() error {
+	if r.Question == "" {
+		return errors.New("models: clarification question must not be empty")
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"git.nkagami.me/natsukagami/kjudge/db"
+	"github.com/pkg/errors"
+)
+
+// Verify checks that the Contest is well-formed before it is written to
+// the Database.
+func (r *Contest) Verify() error {
+	if r.Name == "" {
+		return errors.New("models: contest name must not be empty")
+	}
+	return nil
+}
+
+// GetContests gets every Contest in the Database, ordered by start time.
+func GetContests(db db.DBContext) ([]*Contest, error) {
+	var result []*Contest
+	if err := db.Select(&result, "SELECT * FROM contests ORDER BY start_time"); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,8 @@
+package models
+
+import "github.com/pkg/errors"
+
+// ErrConflict is returned by Write on a table with optimistic locking
+// (a "version" column) when the row was modified concurrently, i.e. the
+// "UPDATE ... WHERE version = ?" matched no rows.
+var ErrConflict = errors.New("models: row was modified concurrently")
@@ -116,6 +116,41 @@ func Marks(keys map[string]string) string {
 	return strings.Join(s, ", ")
 }
 
+// Reserved, underscore-prefixed keys in a TomlTable configure codegen
+// behaviour for the table rather than naming a column; see the comment
+// above each in models.toml. TableFromToml strips them out of Fields
+// before they ever reach a template.
+const (
+	flagSoftDelete     = "_soft_delete"
+	flagTimestamps     = "_timestamps"
+	flagOptimisticLock = "_optimistic_lock"
+)
+
+// isFlag reports whether a models.toml key is a reserved codegen flag
+// rather than a column name.
+func isFlag(key string) bool {
+	return strings.HasPrefix(key, "_")
+}
+
+// boolFlag reads a reserved table-level flag, defaulting to false when
+// absent. Any other value is a typo in models.toml and fails the build
+// loudly rather than silently doing nothing.
+func boolFlag(t TomlTable, name string) bool {
+	v, ok := t[name]
+	if !ok {
+		return false
+	}
+	switch v {
+	case "true":
+		return true
+	case "false":
+		return false
+	default:
+		log.Fatalf("models.toml: %s must be \"true\" or \"false\", got %q", name, v)
+		return false
+	}
+}
+
 // Table is a table representation.
 // The rule for deriving PrimaryKeys is:
 // - If the table has an "id" field, then "PrimaryKeys" is exactly "id".
@@ -126,6 +161,23 @@ type Table struct {
 	Fields      map[string]string
 	PrimaryKeys map[string]string
 	ForeignKeys map[string]string
+
+	// SoftDelete is set by the "_soft_delete" flag: Delete sets the
+	// "deleted_at" column instead of removing the row, and generated
+	// getters exclude soft-deleted rows. Only the primary-key getter has a
+	// WithDeleted variant that includes them; foreign-key getters have none.
+	SoftDelete bool
+	// HasCreatedAt is set by the "_timestamps" flag: Write populates
+	// "created_at" once, on insert.
+	HasCreatedAt bool
+	// HasUpdatedAt is set by the "_timestamps" flag: Write populates
+	// "updated_at" on every write.
+	HasUpdatedAt bool
+	// OptimisticLock is set by the "_optimistic_lock" flag: Write performs
+	// an "UPDATE ... WHERE version = ?" and returns ErrConflict if no row
+	// matched. Only UpdateOrInsertTemplate (tables with an int "id") reads
+	// it; UpsertTemplate ignores it.
+	OptimisticLock bool
 }
 
 // FieldsWithoutID returns a map of fields excluding the ID row.
@@ -139,12 +191,20 @@ func (t *Table) FieldsWithoutID() map[string]string {
 	return res
 }
 
-// TableFromToml parses out a Table from its TOML.
+// TableFromToml parses out a Table from its TOML, resolving the reserved
+// flags described in models.toml against the columns actually present and
+// failing loudly if they disagree (e.g. "_soft_delete" set without a
+// "deleted_at" column).
 func TableFromToml(tables TomlTables, name string, t TomlTable) *Table {
+	fields := make(map[string]string)
 	pks := make(map[string]string)
 	fks := make(map[string]string)
 	upsert := true
 	for field, typ := range t {
+		if isFlag(field) {
+			continue
+		}
+		fields[field] = typ
 		if strings.HasSuffix(field, "_id") {
 			if _, ok := tables[field[:len(field)-len("_id")]+"s"]; ok {
 				pks[field] = typ
@@ -152,16 +212,37 @@ func TableFromToml(tables TomlTables, name string, t TomlTable) *Table {
 			}
 		}
 	}
-	if v, ok := t["id"]; ok {
+	if v, ok := fields["id"]; ok {
 		pks = map[string]string{"id": v}
 		upsert = !(v == "int")
 	}
+
+	softDelete := boolFlag(t, flagSoftDelete)
+	timestamps := boolFlag(t, flagTimestamps)
+	optimisticLock := boolFlag(t, flagOptimisticLock)
+	requireColumn := func(flag, column string, set bool) {
+		if !set {
+			return
+		}
+		if _, ok := fields[column]; !ok {
+			log.Fatalf("models.toml: table %q sets %s but has no %q column", name, flag, column)
+		}
+	}
+	requireColumn(flagSoftDelete, "deleted_at", softDelete)
+	requireColumn(flagTimestamps, "created_at", timestamps)
+	requireColumn(flagTimestamps, "updated_at", timestamps)
+	requireColumn(flagOptimisticLock, "version", optimisticLock)
+
 	return &Table{
-		Name:        name,
-		Upsert:      upsert,
-		Fields:      t,
-		PrimaryKeys: pks,
-		ForeignKeys: fks,
+		Name:           name,
+		Upsert:         upsert,
+		Fields:         fields,
+		PrimaryKeys:    pks,
+		ForeignKeys:    fks,
+		SoftDelete:     softDelete,
+		HasCreatedAt:   timestamps,
+		HasUpdatedAt:   timestamps,
+		OptimisticLock: optimisticLock,
 	}
 }
 
@@ -172,6 +253,7 @@ package models
 
 import (
     "database/sql"
+    {{if or .HasCreatedAt .HasUpdatedAt}}"time"{{end}}
     "github.com/pkg/errors"
     "git.nkagami.me/natsukagami/kjudge/db"
 )
@@ -189,16 +271,30 @@ type {{$name}} struct {
 {{- end}}
 }
 
+{{$deletedCond := ""}}
+{{if .SoftDelete}}{{$deletedCond = " AND deleted_at IS NULL"}}{{end}}
+
 {{/* Primary Key getter */}}
 {{$fn_name := print "Get" $name}}
 // {{$fn_name}} gets a {{$name}} from the Database.
+{{if .SoftDelete}}// Soft-deleted rows are excluded; use {{$fn_name}}WithDeleted to include them.{{end}}
 func {{$fn_name}}(db db.DBContext {{- range $field, $type := .PrimaryKeys -}} , {{param $field}} {{$type}} {{- end}}) (*{{$name}}, error) {
+    var result {{$name}}
+    if err := db.Get(&result, "SELECT * FROM {{.Name}} WHERE {{condition .PrimaryKeys " AND "}}{{$deletedCond}}", {{args .PrimaryKeys ""}}); err != nil {
+        return nil, errors.WithStack(err)
+    }
+    return &result, nil
+}
+{{if .SoftDelete}}
+// {{$fn_name}}WithDeleted gets a {{$name}} from the Database, including soft-deleted rows.
+func {{$fn_name}}WithDeleted(db db.DBContext {{- range $field, $type := .PrimaryKeys -}} , {{param $field}} {{$type}} {{- end}}) (*{{$name}}, error) {
     var result {{$name}}
     if err := db.Get(&result, "SELECT * FROM {{.Name}} WHERE {{condition .PrimaryKeys " AND "}}", {{args .PrimaryKeys ""}}); err != nil {
         return nil, errors.WithStack(err)
     }
     return &result, nil
 }
+{{end}}
 
 {{/* All foreign key getters */}}
 {{range $fk, $fktype := .ForeignKeys -}}
@@ -207,7 +303,7 @@ func {{$fn_name}}(db db.DBContext {{- range $field, $type := .PrimaryKeys -}} ,
 // {{$fn_name}} gets a list of {{$name}} belonging to a {{$fk | fkey}}.
 func {{$fn_name}}(db db.DBContext, {{param $fk}} {{$fktype}}) ([]*{{$name}}, error) {
     var result []*{{$name}}
-    if err := db.Select(&result, "SELECT * FROM {{.Name}} WHERE {{$fk}} = ?", {{param $fk}}); err != nil {
+    if err := db.Select(&result, "SELECT * FROM {{.Name}} WHERE {{$fk}} = ?{{$deletedCond}}", {{param $fk}}); err != nil {
         return nil, errors.WithStack(err)
     }
     return result, nil
@@ -223,11 +319,25 @@ func {{$fn_name}}(db db.DBContext, {{param $fk}} {{$fktype}}) ([]*{{$name}}, err
 {{end}}
 
 {{/* Delete */}}
+{{if .SoftDelete}}
+// Delete soft-deletes the {{$name}}, setting deleted_at instead of removing the row.
+func (r *{{$name}}) Delete(db db.DBContext) error {
+    _, err := db.Exec("UPDATE {{.Name}} SET deleted_at = CURRENT_TIMESTAMP WHERE {{condition .PrimaryKeys " AND "}}", {{args .PrimaryKeys "r"}})
+    return errors.WithStack(err)
+}
+
+// Restore clears deleted_at on the {{$name}}, undoing a prior Delete.
+func (r *{{$name}}) Restore(db db.DBContext) error {
+    _, err := db.Exec("UPDATE {{.Name}} SET deleted_at = NULL WHERE {{condition .PrimaryKeys " AND "}}", {{args .PrimaryKeys "r"}})
+    return errors.WithStack(err)
+}
+{{else}}
 // Delete deletes the {{$name}} from the Database.
 func (r *{{$name}}) Delete(db db.DBContext) error {
     _, err := db.Exec("DELETE FROM {{.Name}} WHERE {{condition .PrimaryKeys " AND "}}", {{args .PrimaryKeys "r"}})
     return errors.WithStack(err)
 }
+{{end}}
 `
 const UpsertTemplate = `
 {{$name := .Name | struct}}
@@ -237,6 +347,12 @@ func (r *{{$name}}) Write(db db.DBContext) error {
     if err := r.Verify(); err != nil {
         return err
     }
+    {{if .HasCreatedAt}}if r.CreatedAt.IsZero() {
+        r.CreatedAt = time.Now()
+    }
+    {{end}}
+    {{if .HasUpdatedAt}}r.UpdatedAt = time.Now()
+    {{end}}
     _, err := db.Exec("INSERT INTO {{.Name}}({{args .Fields "-"}}) VALUES ({{marks .Fields}}) ON CONFLICT ({{args .PrimaryKeys "-"}}) DO UPDATE SET {{condition .Fields ", "}}",
                         {{args .Fields "r"}}, {{args .Fields "r"}})
     return errors.WithStack(err)
@@ -252,8 +368,12 @@ func (r *{{$name}}) Write(db db.DBContext) error {
     if err := r.Verify(); err != nil {
         return err
     }
+    {{if .HasUpdatedAt}}r.UpdatedAt = time.Now()
+    {{end}}
     {{if eq (index .Fields "id") "int"}}
     if r.ID == 0 {
+        {{if .HasCreatedAt}}r.CreatedAt = time.Now(){{end}}
+        {{if .OptimisticLock}}r.Version = 1{{end}}
         {{ $fields := .FieldsWithoutID }}
         res, err := db.Exec("INSERT INTO {{.Name}}({{args $fields "-"}}) VALUES ({{marks $fields}})", {{args $fields "r"}})
         if err != nil {
@@ -267,9 +387,29 @@ func (r *{{$name}}) Write(db db.DBContext) error {
         return nil
     }
     {{end}}
+    {{if .OptimisticLock}}
+    oldVersion := r.Version
+    r.Version++
+    res, err := db.Exec("UPDATE {{.Name}} SET {{condition .Fields ", "}} WHERE {{condition .PrimaryKeys " AND "}} AND version = ?",
+                      {{args .Fields "r"}}, {{args .PrimaryKeys "r"}}, oldVersion)
+    if err != nil {
+        r.Version = oldVersion
+        return errors.WithStack(err)
+    }
+    affected, err := res.RowsAffected()
+    if err != nil {
+        return errors.WithStack(err)
+    }
+    if affected == 0 {
+        r.Version = oldVersion
+        return ErrConflict
+    }
+    return nil
+    {{else}}
     _, err := db.Exec("UPDATE {{.Name}} SET {{condition .Fields ", "}} WHERE {{condition .PrimaryKeys " AND "}}",
                       {{args .Fields "r"}}, {{args .PrimaryKeys "r"}})
     return errors.WithStack(err)
+    {{end}}
 }
 `
 
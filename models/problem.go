@@ -0,0 +1,12 @@
+package models
+
+import "github.com/pkg/errors"
+
+// Verify checks that the Problem is well-formed before it is written to
+// the Database.
+func (r *Problem) Verify() error {
+	if r.Name == "" {
+		return errors.New("models: problem name must not be empty")
+	}
+	return nil
+}
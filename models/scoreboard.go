@@ -0,0 +1,66 @@
+package models
+
+import (
+	"sort"
+
+	"git.nkagami.me/natsukagami/kjudge/db"
+)
+
+// ScoreboardRow is one user's standing within a contest, as computed by
+// Scoreboard. The JSON tags are load-bearing: this is exactly what gets
+// marshalled over the scoreboard SSE stream (see server/contests/sse.go)
+// and read back by the "contests/scoreboard" template's JS.
+type ScoreboardRow struct {
+	User       *User   `json:"user"`
+	TotalScore float64 `json:"totalScore"`
+	Rank       int     `json:"rank"`
+}
+
+// Scoreboard computes contest's standings: each user's total score is the
+// sum of their best score on every problem in the contest, and rows are
+// ranked highest-first, with tied totals sharing a rank.
+func Scoreboard(db db.DBContext, contest *Contest) ([]*ScoreboardRow, error) {
+	problems, err := GetContestProblems(db, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]float64)
+	for _, problem := range problems {
+		submissions, err := GetProblemSubmissions(db, problem.ID)
+		if err != nil {
+			return nil, err
+		}
+		best := make(map[string]float64)
+		for _, submission := range submissions {
+			if !submission.Score.Valid {
+				continue
+			}
+			if submission.Score.Float64 > best[submission.UserID] {
+				best[submission.UserID] = submission.Score.Float64
+			}
+		}
+		for userID, score := range best {
+			totals[userID] += score
+		}
+	}
+
+	rows := make([]*ScoreboardRow, 0, len(totals))
+	for userID, total := range totals {
+		user, err := GetUser(db, userID)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, &ScoreboardRow{User: user, TotalScore: total})
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].TotalScore > rows[j].TotalScore })
+	for i, row := range rows {
+		if i > 0 && row.TotalScore == rows[i-1].TotalScore {
+			row.Rank = rows[i-1].Rank
+		} else {
+			row.Rank = i + 1
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,15 @@
+package models
+
+import "github.com/pkg/errors"
+
+// Verify checks that the Submission is well-formed before it is written
+// to the Database.
+func (r *Submission) Verify() error {
+	if r.ProblemID == 0 {
+		return errors.New("models: submission must belong to a problem")
+	}
+	if r.UserID == "" {
+		return errors.New("models: submission must belong to a user")
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package models
+
+import "github.com/pkg/errors"
+
+// Verify checks that the User is well-formed before it is written to the
+// Database.
+func (r *User) Verify() error {
+	if r.ID == "" {
+		return errors.New("models: user id must not be empty")
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Package pubsub implements a tiny in-process publish/subscribe broker
+// used to push submission and scoreboard updates to long-lived HTTP
+// connections (SSE, websockets) as they happen, instead of having clients
+// poll the database.
+package pubsub
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Topic identifies a stream of events that subscribers can listen to,
+// e.g. one contest's scoreboard or one submission's status.
+type Topic string
+
+// SubmissionTopic is the topic a submission's status updates are
+// published to as it moves through the judging worker.
+func SubmissionTopic(submissionID int) Topic {
+	return Topic(fmt.Sprintf("submission:%d", submissionID))
+}
+
+// ScoreboardTopic is the topic a contest's scoreboard updates are
+// published to whenever a row changes.
+func ScoreboardTopic(contestID int) Topic {
+	return Topic(fmt.Sprintf("scoreboard:%d", contestID))
+}
+
+// AllSubmissionsTopic is the topic every submission's status updates are
+// published to, in addition to its own SubmissionTopic. It exists for
+// consumers that want a single firehose of verdicts rather than one
+// stream per submission, e.g. the GraphQL "submissionVerdict" subscription
+// used by external bots and dashboards.
+func AllSubmissionsTopic() Topic {
+	return Topic("submissions")
+}
+
+// Broker fans out published events to every current subscriber of a topic.
+// The zero value is not usable; create one with New.
+type Broker struct {
+	mu   sync.Mutex
+	subs map[Topic]map[chan interface{}]struct{}
+}
+
+// New creates an empty Broker.
+func New() *Broker {
+	return &Broker{subs: make(map[Topic]map[chan interface{}]struct{})}
+}
+
+// Subscribe registers a new listener on topic. The caller must call the
+// returned cancel func once it stops reading from ch, typically when its
+// request context is done.
+func (b *Broker) Subscribe(topic Topic) (ch chan interface{}, cancel func()) {
+	ch = make(chan interface{}, 8)
+	b.mu.Lock()
+	if b.subs[topic] == nil {
+		b.subs[topic] = make(map[chan interface{}]struct{})
+	}
+	b.subs[topic][ch] = struct{}{}
+	b.mu.Unlock()
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subs[topic], ch)
+		if len(b.subs[topic]) == 0 {
+			delete(b.subs, topic)
+		}
+		b.mu.Unlock()
+		close(ch)
+	}
+}
+
+// Publish notifies every current subscriber of topic. A subscriber that
+// isn't keeping up has the event dropped for it rather than blocking the
+// publisher.
+func (b *Broker) Publish(topic Topic, v interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[topic] {
+		select {
+		case ch <- v:
+		default:
+		}
+	}
+}
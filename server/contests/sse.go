@@ -0,0 +1,104 @@
+// Package contests serves the live Server-Sent Events streams that back
+// the "contests/scoreboard" and
+// "contests/submission" templates.
+package contests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"git.nkagami.me/natsukagami/kjudge/db"
+	"git.nkagami.me/natsukagami/kjudge/models"
+	"git.nkagami.me/natsukagami/kjudge/pubsub"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// Group is the route group serving the contest SSE streams.
+type Group struct {
+	db     *db.DB
+	broker *pubsub.Broker
+}
+
+// New creates a contests Group backed by broker. The judging worker
+// publishes to the same broker whenever a submission transitions state or
+// a scoreboard row changes.
+func New(db *db.DB, broker *pubsub.Broker) *Group {
+	return &Group{db: db, broker: broker}
+}
+
+// Register mounts the group's streaming routes onto e.
+func (g *Group) Register(e *echo.Echo) {
+	e.GET("/contests/:id/scoreboard/stream", g.ScoreboardStream)
+	e.GET("/user/submissions/:id/stream", g.SubmissionStream)
+}
+
+// ScoreboardStream implements GET /contests/:id/scoreboard/stream.
+func (g *Group) ScoreboardStream(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+	contest, err := models.GetContest(g.db, id)
+	if err != nil {
+		return err
+	}
+	return g.stream(c, pubsub.ScoreboardTopic(contest.ID))
+}
+
+// SubmissionStream implements GET /user/submissions/:id/stream.
+func (g *Group) SubmissionStream(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+	submission, err := models.GetSubmission(g.db, id)
+	if err != nil {
+		return err
+	}
+	return g.stream(c, pubsub.SubmissionTopic(submission.ID))
+}
+
+// stream subscribes to topic and relays every published event to c as an
+// SSE frame until the client disconnects.
+func (g *Group) stream(c echo.Context, topic pubsub.Topic) error {
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+
+	ch, cancel := g.broker.Subscribe(topic)
+	defer cancel()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case v, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := writeEvent(c, v); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writeEvent writes a single Server-Sent Event frame carrying v as JSON.
+func writeEvent(c echo.Context, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := fmt.Fprintf(c.Response(), "data: %s\n\n", b); err != nil {
+		return errors.WithStack(err)
+	}
+	c.Response().Flush()
+	return nil
+}
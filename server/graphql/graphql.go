@@ -0,0 +1,139 @@
+// Package graphql wires the "git.nkagami.me/natsukagami/kjudge/graphql"
+// schema into the echo server, next to the admin and user route groups.
+package graphql
+
+import (
+	"context"
+	"net/http"
+
+	"git.nkagami.me/natsukagami/kjudge/db"
+	kjgraphql "git.nkagami.me/natsukagami/kjudge/graphql"
+	"git.nkagami.me/natsukagami/kjudge/pubsub"
+	"github.com/graphql-go/graphql"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// Group is the route group serving the GraphQL endpoint.
+type Group struct {
+	db     *db.DB
+	broker *pubsub.Broker
+}
+
+// New creates a graphql Group. broker should be the same Broker the
+// judging worker publishes submission updates to, so that the
+// "submissionVerdict" subscription sees the same events as the SSE routes
+// in server/contests.
+func New(db *db.DB, broker *pubsub.Broker) *Group {
+	return &Group{db: db, broker: broker}
+}
+
+// Register mounts the group's routes onto e, under "/graphql".
+func (g *Group) Register(e *echo.Echo) {
+	e.POST("/graphql", g.Query)
+	e.GET("/graphql/subscriptions", g.Subscriptions)
+}
+
+type queryRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query implements POST /graphql.
+func (g *Group) Query(c echo.Context) error {
+	var req queryRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	ctx := kjgraphql.WithDB(c.Request().Context(), g.db)
+	ctx = kjgraphql.WithBroker(ctx, g.broker)
+	result := graphql.Do(graphql.Params{
+		Schema:         kjgraphql.Schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        ctx,
+	})
+	return c.JSON(http.StatusOK, result)
+}
+
+var upgrader = websocket.Upgrader{
+	Subprotocols: []string{"graphql-ws"},
+	// Same-origin contest UI and external bots/dashboards alike talk to
+	// this endpoint; the GraphQL layer itself is read-only and unauthenticated,
+	// matching the rest of the public contest API.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// wsMessage is a minimal subset of the graphql-ws protocol: enough to
+// start a subscription and stream data and complete frames back. No
+// separate "error" frame is sent: GraphQL errors travel inside a data
+// frame's payload.
+type wsMessage struct {
+	Type    string        `json:"type"`
+	ID      string        `json:"id,omitempty"`
+	Payload *queryRequest `json:"payload,omitempty"`
+}
+
+// Subscriptions implements GET /graphql/subscriptions, upgrading to a
+// websocket and streaming live submission verdict updates. The schema has
+// no scoreboard subscription, so scoreboard changes are not streamed here.
+func (g *Group) Subscriptions(c echo.Context) error {
+	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return nil
+		}
+		switch msg.Type {
+		case "connection_init":
+			_ = conn.WriteJSON(wsMessage{Type: "connection_ack"})
+		case "start":
+			if msg.Payload == nil {
+				continue
+			}
+			go g.runSubscription(ctx, conn, msg.ID, *msg.Payload)
+		case "stop":
+			cancel()
+			return nil
+		}
+	}
+}
+
+func (g *Group) runSubscription(ctx context.Context, conn *websocket.Conn, id string, req queryRequest) {
+	subCtx := kjgraphql.WithDB(ctx, g.db)
+	subCtx = kjgraphql.WithBroker(subCtx, g.broker)
+	result := graphql.Subscribe(graphql.Params{
+		Schema:         kjgraphql.Schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        subCtx,
+	})
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case r, ok := <-result:
+			if !ok {
+				_ = conn.WriteJSON(wsMessage{Type: "complete", ID: id})
+				return
+			}
+			_ = conn.WriteJSON(struct {
+				Type    string      `json:"type"`
+				ID      string      `json:"id"`
+				Payload interface{} `json:"payload"`
+			}{Type: "data", ID: id, Payload: r})
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Package server assembles kjudge's echo.Echo instance: it builds the
+// shared pubsub.Broker, wires up each route group, and installs the
+// renderer pages are served with.
+package server
+
+import (
+	"git.nkagami.me/natsukagami/kjudge/db"
+	"git.nkagami.me/natsukagami/kjudge/pubsub"
+	"git.nkagami.me/natsukagami/kjudge/server/contests"
+	graphqlgroup "git.nkagami.me/natsukagami/kjudge/server/graphql"
+	"git.nkagami.me/natsukagami/kjudge/server/template"
+	"github.com/labstack/echo/v4"
+)
+
+// New builds kjudge's echo.Echo and the pubsub.Broker backing its live
+// streams. Pass the returned broker to worker.New, so judging results
+// reach the streams registered here.
+func New(d *db.DB, rendererOpts template.RendererOptions) (*echo.Echo, *pubsub.Broker, error) {
+	e := echo.New()
+
+	renderer, err := template.NewRenderer(rendererOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+	e.Renderer = renderer
+
+	broker := pubsub.New()
+	contests.New(d, broker).Register(e)
+	graphqlgroup.New(d, broker).Register(e)
+
+	return e, broker, nil
+}
@@ -2,10 +2,13 @@ package template
 
 import (
 	"encoding/json"
+	"fmt"
 	"html/template"
 	"io"
 	"io/fs"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -64,13 +67,92 @@ func resolveTemplate(name string, into []string) []string {
 	return append(into, name)
 }
 
+// funcRegistry is the set of functions available to every template. It
+// starts out with the built-in funcs below, but other packages (admin,
+// contests, ...) can contribute their own from an init(), via RegisterFunc,
+// without this package needing to know about them.
+var funcRegistry = map[string]interface{}{
+	"time":     func(t time.Time) string { return t.Format(time.RFC1123) },
+	"isFuture": func(t time.Time) bool { return t.After(time.Now()) },
+	"isPast":   func(t time.Time) bool { return t.Before(time.Now()) },
+	"join":     strings.Join,
+	"add":      func(a, b int) int { return a + b },
+	"version":  version,
+	"loggedIn": loggedIn,
+	"json":     func(item interface{}) (string, error) { b, err := json.Marshal(item); return string(b), err },
+	"zip":      func(items ...interface{}) []interface{} { return items },
+}
+
+// RegisterFunc adds fn under name to every template's FuncMap. Call it
+// from an init(), before the server starts parsing templates.
+func RegisterFunc(name string, fn interface{}) {
+	funcRegistry[name] = fn
+}
+
+// PageHook augments the data passed to a page's template just before it is
+// rendered, e.g. to inject contest-wide announcements into every
+// "contests/*" page without every handler duplicating that logic. Hooks
+// run in registration order, each seeing the previous hook's result.
+type PageHook func(c echo.Context, data interface{}) (interface{}, error)
+
+var pageHooks = map[string][]PageHook{}
+
+// RegisterPageHook adds hook to run whenever the template name is
+// rendered. Call it from an init() in the package that owns the hook.
+func RegisterPageHook(name string, hook PageHook) {
+	pageHooks[name] = append(pageHooks[name], hook)
+}
+
+// runPageHooks applies every hook registered for name to data, in
+// registration order.
+func runPageHooks(c echo.Context, name string, data interface{}) (interface{}, error) {
+	var err error
+	for _, hook := range pageHooks[name] {
+		data, err = hook(c, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
 // Renderer implements echo.Renderer
 type Renderer struct{}
 
 var _ echo.Renderer = Renderer{}
 
+// templates holds every template tree parsed once at startup by
+// NewRenderer, keyed by logical name (e.g. "admin/home"). Render and
+// LiveRenderer.Render both execute through renderTemplate, so a page
+// renders identically however it was parsed.
+var templates map[string]*template.Template
+
+// Render looks up name in templates and executes it with data. It is the
+// production counterpart to LiveRenderer.Render's on-the-fly parse.
+func Render(w io.Writer, name string, data interface{}) error {
+	t, ok := templates[name]
+	if !ok {
+		return errors.Errorf("template: no such template %q", name)
+	}
+	return renderTemplate(t, w, data)
+}
+
+// renderTemplate executes t's "root" template, the shared logic behind
+// both Render and LiveRenderer.Render. Every page tree is cloned from the
+// same parsed "templates/root.html" (see parseRootTemplate), which defines
+// the "root" entrypoint and pulls the page in via {{template "content" .}};
+// the page's own top-level body (a bare {{define "content"}} block) is
+// never the thing to execute directly, or the page renders blank.
+func renderTemplate(t *template.Template, w io.Writer, data interface{}) error {
+	return t.ExecuteTemplate(w, "root", data)
+}
+
 // Render implement echo.Renderer.Render
 func (r Renderer) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	data, err := runPageHooks(c, name, data)
+	if err != nil {
+		return err
+	}
 	return Render(w, name, data)
 }
 
@@ -78,14 +160,36 @@ func templateFilename(name string) string {
 	return "templates/" + name + ".html"
 }
 
-func parseTemplateTree(root *template.Template, name string) (*template.Template, error) {
+// templateSource loads the raw contents of a template by its logical name
+// (e.g. "admin/home"). It abstracts over where templates live, so the same
+// tree-building logic below serves both the embedded, parse-once Renderer
+// and the disk-backed, reparse-every-request LiveRenderer.
+type templateSource func(name string) ([]byte, error)
+
+// embedSource reads templates from the binary's embedded filesystem. This
+// is what production always uses.
+func embedSource(name string) ([]byte, error) {
+	return fs.ReadFile(embed.Content, templateFilename(name))
+}
+
+// diskSource reads templates from an on-disk directory, for LiveRenderer.
+// dir is expected to point directly at the templates tree (e.g.
+// "./templates"), so unlike embedSource this does not go through
+// templateFilename, which would double up the "templates/" prefix.
+func diskSource(dir string) templateSource {
+	return func(name string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, name+".html"))
+	}
+}
+
+func parseTemplateTree(root *template.Template, name string, src templateSource) (*template.Template, error) {
 	names := resolveTemplate(name, nil)
 	t, err := root.Clone()
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 	for _, name := range names {
-		content, err := fs.ReadFile(embed.Content, templateFilename(name))
+		content, err := src(name)
 		if err != nil {
 			return nil, errors.Wrapf(err, "file %s", name)
 		}
@@ -96,24 +200,14 @@ func parseTemplateTree(root *template.Template, name string) (*template.Template
 	return t, nil
 }
 
-func parseRootTemplate() (*template.Template, error) {
-	root, err := fs.ReadFile(embed.Content, "templates/root.html")
+func parseRootTemplate(src templateSource) (*template.Template, error) {
+	root, err := src("root")
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 	tRoot := template.New("")
-	// Include a bunch of funcs
-	tRoot.Funcs(map[string]interface{}{
-		"time":     func(t time.Time) string { return t.Format(time.RFC1123) },
-		"isFuture": func(t time.Time) bool { return t.After(time.Now()) },
-		"isPast":   func(t time.Time) bool { return t.Before(time.Now()) },
-		"join":     strings.Join,
-		"add":      func(a, b int) int { return a + b },
-		"version":  version,
-		"loggedIn": loggedIn,
-		"json":     func(item interface{}) (string, error) { b, err := json.Marshal(item); return string(b), err },
-		"zip":      func(items ...interface{}) []interface{} { return items },
-	})
+	// Include every func contributed to funcRegistry.
+	tRoot.Funcs(funcRegistry)
 	tRoot, err = tRoot.Parse(string(root))
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -121,9 +215,15 @@ func parseRootTemplate() (*template.Template, error) {
 	return tRoot, nil
 }
 
-// Searches for and load all html templates.
+// Searches for and load all html templates from the embedded filesystem.
 func parseAllTemplates() (map[string]*template.Template, error) {
-	tRoot, err := parseRootTemplate()
+	return parseAllTemplatesFrom(embedSource)
+}
+
+// parseAllTemplatesFrom loads all html templates using src, which may read
+// from the embedded filesystem (production) or from disk (LiveRenderer).
+func parseAllTemplatesFrom(src templateSource) (map[string]*template.Template, error) {
+	tRoot, err := parseRootTemplate(src)
 	if err != nil {
 		return nil, err
 	}
@@ -131,7 +231,7 @@ func parseAllTemplates() (map[string]*template.Template, error) {
 	names := []string{}
 	for file := range templateList {
 		names = append(names, file)
-		t, err := parseTemplateTree(tRoot, file)
+		t, err := parseTemplateTree(tRoot, file, src)
 		if err != nil {
 			return nil, errors.Wrapf(err, "file %s", file)
 		}
@@ -140,3 +240,70 @@ func parseAllTemplates() (map[string]*template.Template, error) {
 	log.Printf("defined templates: %s", strings.Join(names, ", "))
 	return mp, nil
 }
+
+// RendererOptions configures how NewRenderer loads templates.
+type RendererOptions struct {
+	// Dev turns on live-reload mode: templates are read from TemplatesDir
+	// and reparsed on every request instead of being parsed once from the
+	// embedded filesystem at startup. Intended for local development only,
+	// e.g. behind a "-dev" flag.
+	Dev bool
+	// TemplatesDir is the on-disk template directory used when Dev is set,
+	// e.g. "./templates". Ignored otherwise.
+	TemplatesDir string
+}
+
+// NewRenderer builds the echo.Renderer kjudge serves HTML with. With Dev
+// unset, it parses all templates once from the embedded filesystem, the
+// same production behavior as before this function existed. With Dev set,
+// it returns a LiveRenderer that reparses from TemplatesDir on every
+// request, so template edits show up without a rebuild.
+func NewRenderer(opts RendererOptions) (echo.Renderer, error) {
+	if opts.Dev {
+		return &LiveRenderer{dir: opts.TemplatesDir}, nil
+	}
+	parsed, err := parseAllTemplates()
+	if err != nil {
+		return nil, err
+	}
+	templates = parsed
+	return Renderer{}, nil
+}
+
+// LiveRenderer implements echo.Renderer by reparsing templates from an
+// on-disk directory on every request. Parse errors are rendered directly
+// into the response instead of surfacing as a bare 500, so a broken edit
+// is visible in the browser immediately.
+type LiveRenderer struct {
+	dir string
+}
+
+var _ echo.Renderer = (*LiveRenderer)(nil)
+
+// Render implements echo.Renderer.Render.
+func (r *LiveRenderer) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	data, err := runPageHooks(c, name, data)
+	if err != nil {
+		return err
+	}
+	fresh, err := parseAllTemplatesFrom(diskSource(r.dir))
+	if err != nil {
+		return writeParseError(w, err)
+	}
+	t, ok := fresh[name]
+	if !ok {
+		return errors.Errorf("template: no such template %q", name)
+	}
+	return renderTemplate(t, w, data)
+}
+
+// writeParseError renders a minimal HTML error page describing a template
+// parse error directly into w.
+func writeParseError(w io.Writer, err error) error {
+	_, writeErr := fmt.Fprintf(w,
+		"<!DOCTYPE html><html><head><title>Template error</title></head>"+
+			"<body style=\"font-family: monospace; white-space: pre-wrap; padding: 2em;\">"+
+			"<h1>Template parse error</h1>%s</body></html>",
+		template.HTMLEscapeString(err.Error()))
+	return writeErr
+}
@@ -0,0 +1,60 @@
+// Package worker is the integration point between judging and kjudge's
+// live subscriptions: whenever a submission's state is written back to
+// the Database, it publishes the update (and the contest's resulting
+// scoreboard) to a pubsub.Broker, so the SSE and GraphQL subscription
+// endpoints can push it to clients instead of having them poll.
+package worker
+
+import (
+	"git.nkagami.me/natsukagami/kjudge/db"
+	"git.nkagami.me/natsukagami/kjudge/models"
+	"git.nkagami.me/natsukagami/kjudge/pubsub"
+)
+
+// Worker reports submission results as the judging pipeline produces them.
+type Worker struct {
+	db     *db.DB
+	broker *pubsub.Broker
+}
+
+// New creates a Worker backed by broker. Pass the same broker given to
+// server.New, so the contest UI's SSE streams and the GraphQL
+// subscription endpoint see the same events.
+func New(db *db.DB, broker *pubsub.Broker) *Worker {
+	return &Worker{db: db, broker: broker}
+}
+
+// Report writes submission's current verdict and score to the Database
+// and publishes the resulting submission and scoreboard updates. Call it
+// every time a submission transitions state, e.g. once per test case and
+// once more when the final verdict is decided.
+func (w *Worker) Report(submission *models.Submission) error {
+	if err := submission.Write(w.db); err != nil {
+		return err
+	}
+	w.publish(submission)
+	return nil
+}
+
+// publish notifies subscribers of submission's new state and, best
+// effort, of the contest's recomputed scoreboard. A failure to look up
+// the scoreboard is not fatal to Report: the submission update itself has
+// already been written and published.
+func (w *Worker) publish(submission *models.Submission) {
+	w.broker.Publish(pubsub.SubmissionTopic(submission.ID), submission)
+	w.broker.Publish(pubsub.AllSubmissionsTopic(), submission)
+
+	problem, err := models.GetProblem(w.db, submission.ProblemID)
+	if err != nil {
+		return
+	}
+	contest, err := models.GetContest(w.db, problem.ContestID)
+	if err != nil {
+		return
+	}
+	scoreboard, err := models.Scoreboard(w.db, contest)
+	if err != nil {
+		return
+	}
+	w.broker.Publish(pubsub.ScoreboardTopic(contest.ID), scoreboard)
+}